@@ -0,0 +1,99 @@
+package memcache
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+)
+
+// ErrTLSUnsupported is returned by WithTLS when the configured Discoverer
+// doesn't implement tlsDiscoverer.
+var ErrTLSUnsupported = errors.New("memcache: discoverer does not support TLS")
+
+// ErrSASLUnsupported is returned by WithSASL when the configured Discoverer
+// doesn't implement saslDiscoverer.
+var ErrSASLUnsupported = errors.New("memcache: discoverer does not support SASL auth")
+
+// opSASLAuth is the binary protocol opcode for a "SASL AUTH" request.
+const opSASLAuth = 0x21
+
+// tlsDiscoverer is implemented by Discoverers that can dial their
+// configuration endpoint over TLS, such as AWSAutoDiscoverer.
+type tlsDiscoverer interface {
+	SetTLSConfig(cfg *tls.Config)
+}
+
+// saslDiscoverer is implemented by Discoverers that can authenticate a
+// newly dialed connection with SASL PLAIN, such as AWSAutoDiscoverer.
+type saslDiscoverer interface {
+	SetSASLAuth(username, password string)
+}
+
+// WithTLS configures the Discovery's Discoverer to dial over TLS using cfg,
+// for Discoverers that support it (currently AWSAutoDiscoverer). Use the
+// same cfg when constructing the Client so the config endpoint and the
+// data-plane connections it discovers agree on certificates and SNI.
+func WithTLS(cfg *tls.Config) Option {
+	return func(d *Discovery) error {
+		t, ok := d.discoverer.(tlsDiscoverer)
+		if !ok {
+			return ErrTLSUnsupported
+		}
+		t.SetTLSConfig(cfg)
+		return nil
+	}
+}
+
+// WithSASL configures the Discovery's Discoverer to authenticate each newly
+// dialed connection with the binary-protocol SASL PLAIN mechanism, for
+// Discoverers that support it (currently AWSAutoDiscoverer).
+func WithSASL(username, password string) Option {
+	return func(d *Discovery) error {
+		s, ok := d.discoverer.(saslDiscoverer)
+		if !ok {
+			return ErrSASLUnsupported
+		}
+		s.SetSASLAuth(username, password)
+		return nil
+	}
+}
+
+// saslAuthPlain performs a binary-protocol "SASL AUTH PLAIN" handshake over
+// conn, authenticating as username/password, and returns an error if the
+// server responds with a non-zero status.
+func saslAuthPlain(conn net.Conn, username, password string) error {
+	mechanism := "PLAIN"
+	body := "\x00" + username + "\x00" + password
+
+	req := make([]byte, 24+len(mechanism)+len(body))
+	req[0] = 0x80 // magic: request
+	req[1] = opSASLAuth
+	binary.BigEndian.PutUint16(req[2:4], uint16(len(mechanism))) // key length
+	binary.BigEndian.PutUint32(req[8:12], uint32(len(mechanism)+len(body)))
+	copy(req[24:], mechanism)
+	copy(req[24+len(mechanism):], body)
+
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	header := make([]byte, 24)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return err
+	}
+
+	status := binary.BigEndian.Uint16(header[6:8])
+	bodyLen := binary.BigEndian.Uint32(header[8:12])
+	if bodyLen > 0 {
+		if _, err := io.CopyN(io.Discard, conn, int64(bodyLen)); err != nil {
+			return err
+		}
+	}
+	if status != 0 {
+		return fmt.Errorf("memcache: SASL auth failed with status 0x%x", status)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package memcache
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+)
+
+// plainDiscoverer is a Discoverer that supports neither TLS nor SASL, used
+// to exercise WithTLS/WithSASL's unsupported-Discoverer error paths.
+type plainDiscoverer struct{}
+
+func (plainDiscoverer) Discover(ctx context.Context) ([]string, error) {
+	return []string{"127.0.0.1:11211"}, nil
+}
+
+func TestWithTLS_UnsupportedDiscoverer(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewDiscovery(context.Background(), plainDiscoverer{}, WithTLS(&tls.Config{}))
+	if !errors.Is(err, ErrTLSUnsupported) {
+		t.Fatalf("NewDiscovery() error = %v, want %v", err, ErrTLSUnsupported)
+	}
+}
+
+func TestWithSASL_UnsupportedDiscoverer(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewDiscovery(context.Background(), plainDiscoverer{}, WithSASL("user", "pass"))
+	if !errors.Is(err, ErrSASLUnsupported) {
+		t.Fatalf("NewDiscovery() error = %v, want %v", err, ErrSASLUnsupported)
+	}
+}
+
+func TestSaslAuthPlain(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		status  uint16
+		wantErr bool
+	}{
+		{name: "status 0 succeeds", status: 0x0000, wantErr: false},
+		{name: "non-zero status fails", status: 0x0020, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, server := net.Pipe()
+			defer func() { _ = client.Close() }()
+
+			done := make(chan struct{})
+			var gotMechanism string
+			var gotBody string
+			go func() {
+				defer close(done)
+				defer func() { _ = server.Close() }()
+
+				header := make([]byte, 24)
+				if _, err := io.ReadFull(server, header); err != nil {
+					return
+				}
+				keyLen := binary.BigEndian.Uint16(header[2:4])
+				bodyLen := binary.BigEndian.Uint32(header[8:12])
+
+				rest := make([]byte, bodyLen)
+				if _, err := io.ReadFull(server, rest); err != nil {
+					return
+				}
+				gotMechanism = string(rest[:keyLen])
+				gotBody = string(rest[keyLen:])
+
+				resp := make([]byte, 24)
+				resp[0] = 0x81
+				resp[1] = opSASLAuth
+				binary.BigEndian.PutUint16(resp[6:8], tt.status)
+				_, _ = server.Write(resp)
+			}()
+
+			err := saslAuthPlain(client, "user", "pass")
+			<-done
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("saslAuthPlain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotMechanism != "PLAIN" {
+				t.Fatalf("mechanism = %q, want PLAIN", gotMechanism)
+			}
+			if want := "\x00user\x00pass"; gotBody != want {
+				t.Fatalf("body = %q, want %q", gotBody, want)
+			}
+		})
+	}
+}
@@ -3,15 +3,12 @@ package memcache
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
 	"net"
-	"os"
-	"os/signal"
-	"sync"
-	"syscall"
-	"time"
 )
 
 // AWS documentation: https://docs.aws.amazon.com/AmazonElastiCache/latest/mem-ug/AutoDiscovery.html
@@ -21,57 +18,48 @@ var (
 	// ErrAutoDiscover is returned when no nodes are found in the configuration.
 	ErrAutoDiscover   = errors.New("memcache: no nodes found in the configuration")
 	ErrInvalidCommand = errors.New("memcache: error in response")
+
+	// resultEnd marks the end of a "config get cluster" response.
+	resultEnd = []byte("END\r\n")
 )
 
-type Discovery struct {
+// AWSAutoDiscoverer is a Discoverer that speaks the ElastiCache AutoDiscovery
+// protocol: it dials the cluster's configuration endpoint and issues
+// "config get cluster" to get back the current node list.
+type AWSAutoDiscoverer struct {
 	clusterAddress string
-	nodes          []string
-	pollInterval   time.Duration
 
-	mu         sync.RWMutex
-	serverList *ServerList
+	tlsConfig    *tls.Config
+	saslUsername string
+	saslPassword string
 }
 
-// NewAWSDiscoverySelector creates a new Discovery designed to work with the AWS AutoDiscovery feature.
-func NewAWSDiscoverySelector(clusterAddress string, options ...func(*Discovery)) *Discovery {
-	d := &Discovery{
-		clusterAddress: clusterAddress,
-	}
-
-	d.pollInterval = time.Hour
-	for _, option := range options {
-		option(d)
-	}
+// NewAWSDiscoverySelector creates a new Discovery designed to work with the
+// AWS AutoDiscovery feature. It runs the first discovery synchronously,
+// returning any error from it, and then polls in the background until ctx
+// is done or the returned Discovery's Close method is called.
+func NewAWSDiscoverySelector(ctx context.Context, clusterAddress string, options ...Option) (*Discovery, error) {
+	return NewDiscovery(ctx, &AWSAutoDiscoverer{clusterAddress: clusterAddress}, options...)
+}
 
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, syscall.SIGINT, syscall.SIGTERM)
-	ticker := time.NewTicker(d.pollInterval)
-	go func() {
-		_ = d.discover()
-		for {
-			select {
-			case <-c:
-				ticker.Stop()
-			case <-ticker.C:
-				_ = d.discover()
-			}
-		}
-	}()
-	return d
+// SetTLSConfig implements tlsDiscoverer, switching Discover to dial the
+// configuration endpoint over TLS using cfg.
+func (a *AWSAutoDiscoverer) SetTLSConfig(cfg *tls.Config) {
+	a.tlsConfig = cfg
 }
 
-// WithCustomPollInterval sets the poll interval for the Discovery. Default is 1 hour.
-func WithCustomPollInterval(interval time.Duration) func(*Discovery) {
-	return func(d *Discovery) {
-		d.pollInterval = interval
-	}
+// SetSASLAuth implements saslDiscoverer, authenticating the connection to
+// the configuration endpoint with SASL PLAIN once it is dialed.
+func (a *AWSAutoDiscoverer) SetSASLAuth(username, password string) {
+	a.saslUsername = username
+	a.saslPassword = password
 }
 
-// discover fetches the list of nodes from the configuration endpoint.
-func (d *Discovery) discover() error {
-	connection, errDial := net.Dial("tcp", d.clusterAddress)
-	if errDial != nil {
-		return errDial
+// Discover fetches the list of nodes from the configuration endpoint.
+func (a *AWSAutoDiscoverer) Discover(ctx context.Context) ([]string, error) {
+	connection, err := a.dial(ctx)
+	if err != nil {
+		return nil, err
 	}
 	defer func() {
 		_ = connection.Close()
@@ -80,46 +68,38 @@ func (d *Discovery) discover() error {
 	rw := bufio.NewReadWriter(bufio.NewReader(connection), bufio.NewWriter(connection))
 	_, errFprintf := fmt.Fprintf(rw, "config get cluster\r\n")
 	if errFprintf != nil {
-		return errFprintf
+		return nil, errFprintf
 	}
 
 	if errFlush := rw.Flush(); errFlush != nil {
-		return errFlush
-	}
-
-	nodes, errParse := parseNodes(rw)
-	if errParse != nil {
-		return errParse
+		return nil, errFlush
 	}
 
-	if len(nodes) == 0 {
-		return ErrAutoDiscover
-	}
+	return parseNodes(rw)
+}
 
-	replaceNodes := false
-	if d.serverList == nil {
-		replaceNodes = true
+// dial opens a connection to the configuration endpoint, using TLS when
+// SetTLSConfig has been called, and authenticating via SASL PLAIN when
+// SetSASLAuth has been called.
+func (a *AWSAutoDiscoverer) dial(ctx context.Context) (net.Conn, error) {
+	var connection net.Conn
+	var err error
+	if a.tlsConfig != nil {
+		connection, err = (&tls.Dialer{Config: a.tlsConfig}).DialContext(ctx, "tcp", a.clusterAddress)
 	} else {
-		currentNodes := d.serverList.addresses
-		for _, node := range nodes {
-			if !containsNode(currentNodes, node) {
-				replaceNodes = true
-				break
-			}
-		}
+		connection, err = (&net.Dialer{}).DialContext(ctx, "tcp", a.clusterAddress)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	if replaceNodes {
-		var serverList ServerList
-		err := serverList.SetServers(nodes...)
-		if err != nil {
-			return err
+	if a.saslUsername != "" {
+		if err := saslAuthPlain(connection, a.saslUsername, a.saslPassword); err != nil {
+			_ = connection.Close()
+			return nil, err
 		}
-		d.mu.Lock()
-		d.serverList = &serverList
-		d.mu.Unlock()
 	}
-	return nil
+	return connection, nil
 }
 
 func parseNodes(r *bufio.ReadWriter) ([]string, error) {
@@ -157,20 +137,3 @@ func parseNodes(r *bufio.ReadWriter) ([]string, error) {
 		}
 	}
 }
-
-func (d *Discovery) PickServer(key string) (net.Addr, error) {
-	return d.serverList.PickServer(key)
-}
-
-func (d *Discovery) Each(f func(net.Addr) error) error {
-	return d.serverList.Each(f)
-}
-
-func containsNode(nodes []net.Addr, node string) bool {
-	for _, n := range nodes {
-		if n.String() == node {
-			return true
-		}
-	}
-	return false
-}
@@ -3,6 +3,8 @@ package memcache
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"crypto/tls"
 	"reflect"
 	"strings"
 	"testing"
@@ -27,3 +29,41 @@ END\r\n`
 		t.Fatalf("parseNodes() got = %v\nwant %v", strings.Join(nodes, ", "), strings.Join(expectedNodes, ", "))
 	}
 }
+
+func TestAWSAutoDiscoverer_Discover_TLS(t *testing.T) {
+	t.Parallel()
+
+	serverCfg, clientCfg := newLocalTLSConfigs(t)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", serverCfg)
+	if err != nil {
+		t.Fatalf("tls.Listen() error = %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		r := bufio.NewReader(conn)
+		if _, err := r.ReadString('\n'); err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte("node1.use1.cache.amazonaws.com|10.0.0.1|11211\\n\\r\\n \nEND\r\n"))
+	}()
+
+	a := &AWSAutoDiscoverer{clusterAddress: ln.Addr().String()}
+	a.SetTLSConfig(clientCfg)
+
+	nodes, err := a.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	want := []string{"node1.use1.cache.amazonaws.com:11211"}
+	if !reflect.DeepEqual(nodes, want) {
+		t.Fatalf("Discover() = %v, want %v", nodes, want)
+	}
+}
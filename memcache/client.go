@@ -0,0 +1,107 @@
+package memcache
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+)
+
+// ServerSelector is implemented by anything that can route a key to a node
+// address and iterate over the current node set, such as ServerList and
+// Discovery.
+type ServerSelector interface {
+	PickServer(key string) (net.Addr, error)
+	Each(f func(net.Addr) error) error
+}
+
+// Client manages the per-node connections used to talk to a memcached
+// cluster, routing each request through a ServerSelector.
+type Client struct {
+	// Timeout bounds each per-node dial. Zero means no timeout.
+	Timeout time.Duration
+
+	// TLSConfig, when set, dials every per-node connection over TLS,
+	// mirroring the encryption WithTLS applies to a Discovery's
+	// configuration-endpoint connection so the data plane and control
+	// plane agree on certificates and SNI.
+	TLSConfig *tls.Config
+
+	// SASLUsername and SASLPassword, when set, authenticate every newly
+	// dialed per-node connection with SASL PLAIN, mirroring WithSASL on
+	// Discovery.
+	SASLUsername string
+	SASLPassword string
+
+	selector ServerSelector
+}
+
+// New returns a Client backed by a ServerList built from servers.
+func New(servers ...string) (*Client, error) {
+	var ss ServerList
+	if err := ss.SetServers(servers...); err != nil {
+		return nil, err
+	}
+	return NewFromSelector(&ss), nil
+}
+
+// NewFromSelector returns a Client that routes requests through ss, which
+// may be a *ServerList or a *Discovery.
+func NewFromSelector(ss ServerSelector) *Client {
+	return &Client{selector: ss}
+}
+
+// WithTLS configures c to dial every per-node connection over TLS using cfg
+// and returns c for chaining.
+func (c *Client) WithTLS(cfg *tls.Config) *Client {
+	c.TLSConfig = cfg
+	return c
+}
+
+// WithSASL configures c to authenticate every newly dialed per-node
+// connection with SASL PLAIN and returns c for chaining.
+func (c *Client) WithSASL(username, password string) *Client {
+	c.SASLUsername = username
+	c.SASLPassword = password
+	return c
+}
+
+// Conn returns a connection to the node that owns key, routed through the
+// Client's ServerSelector and dialed with the Client's TLS and SASL
+// settings. It is the extension point memcached operations (Get, Set, ...)
+// build on to reach the right node; this package does not implement the
+// memcached text/binary protocol itself.
+func (c *Client) Conn(ctx context.Context, key string) (net.Conn, error) {
+	addr, err := c.selector.PickServer(key)
+	if err != nil {
+		return nil, err
+	}
+	return c.dialNode(ctx, addr)
+}
+
+// dialNode opens a connection to addr, applying the Client's own TLSConfig
+// and SASL credentials. It does not take these settings from the Discovery
+// the Client routes through; WithTLS/WithSASL on a Discovery affect only its
+// configuration-endpoint connection.
+func (c *Client) dialNode(ctx context.Context, addr net.Addr) (net.Conn, error) {
+	netDialer := &net.Dialer{Timeout: c.Timeout}
+
+	var conn net.Conn
+	var err error
+	if c.TLSConfig != nil {
+		conn, err = (&tls.Dialer{NetDialer: netDialer, Config: c.TLSConfig}).DialContext(ctx, addr.Network(), addr.String())
+	} else {
+		conn, err = netDialer.DialContext(ctx, addr.Network(), addr.String())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if c.SASLUsername != "" {
+		if err := saslAuthPlain(conn, c.SASLUsername, c.SASLPassword); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
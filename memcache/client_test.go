@@ -0,0 +1,198 @@
+package memcache
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+)
+
+// newLocalTLSConfigs generates a self-signed certificate for 127.0.0.1 and
+// returns a server tls.Config presenting it and a client tls.Config that
+// trusts it, for use with tls.Listen in tests.
+func newLocalTLSConfigs(t *testing.T) (serverCfg, clientCfg *tls.Config) {
+	t.Helper()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() error = %v", err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate() error = %v", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("x509.ParseCertificate() error = %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+
+	serverCfg = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: priv}}}
+	clientCfg = &tls.Config{RootCAs: pool, ServerName: "127.0.0.1"}
+	return serverCfg, clientCfg
+}
+
+func TestClient_Conn(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	const want = "pong"
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		_, _ = conn.Write([]byte(want))
+	}()
+
+	client, err := New(ln.Addr().String())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	conn, err := client.Conn(context.Background(), "some key")
+	if err != nil {
+		t.Fatalf("Conn() error = %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	got := make([]byte, len(want))
+	if _, err := io.ReadFull(conn, got); err != nil {
+		t.Fatalf("read from Conn() error = %v", err)
+	}
+	if string(got) != want {
+		t.Fatalf("Conn() read = %q, want %q", got, want)
+	}
+}
+
+func TestClient_Conn_TLS(t *testing.T) {
+	t.Parallel()
+
+	serverCfg, clientCfg := newLocalTLSConfigs(t)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", serverCfg)
+	if err != nil {
+		t.Fatalf("tls.Listen() error = %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	const want = "pong"
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		_, _ = conn.Write([]byte(want))
+	}()
+
+	client, err := New(ln.Addr().String())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	client.WithTLS(clientCfg)
+
+	conn, err := client.Conn(context.Background(), "some key")
+	if err != nil {
+		t.Fatalf("Conn() error = %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	got := make([]byte, len(want))
+	if _, err := io.ReadFull(conn, got); err != nil {
+		t.Fatalf("read from Conn() error = %v", err)
+	}
+	if string(got) != want {
+		t.Fatalf("Conn() read = %q, want %q", got, want)
+	}
+}
+
+func TestClient_Conn_SASL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		authStatus uint16
+		wantErr    bool
+	}{
+		{name: "auth succeeds", authStatus: 0, wantErr: false},
+		{name: "auth rejected", authStatus: 0x20, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("net.Listen() error = %v", err)
+			}
+			defer func() { _ = ln.Close() }()
+
+			go serveSASLAuth(ln, tt.authStatus)
+
+			client, err := New(ln.Addr().String())
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			client.WithSASL("user", "pass")
+
+			conn, err := client.Conn(context.Background(), "some key")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Conn() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if conn != nil {
+				_ = conn.Close()
+			}
+		})
+	}
+}
+
+// serveSASLAuth accepts a single connection on ln, reads one binary-protocol
+// SASL AUTH request, and replies with a response header carrying status.
+func serveSASLAuth(ln net.Listener, status uint16) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	header := make([]byte, 24)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return
+	}
+	bodyLen := binary.BigEndian.Uint32(header[8:12])
+	if _, err := io.CopyN(io.Discard, conn, int64(bodyLen)); err != nil {
+		return
+	}
+
+	resp := make([]byte, 24)
+	resp[0] = 0x81 // magic: response
+	resp[1] = opSASLAuth
+	binary.BigEndian.PutUint16(resp[6:8], status)
+	_, _ = conn.Write(resp)
+}
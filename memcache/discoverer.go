@@ -0,0 +1,46 @@
+package memcache
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// DNSSRVDiscoverer discovers nodes by resolving a DNS SRV record, e.g.
+// "_memcached._tcp.example.com", and returning each target as "host:port".
+type DNSSRVDiscoverer struct {
+	Service string // e.g. "memcached"
+	Proto   string // e.g. "tcp"; defaults to "tcp"
+	Domain  string // e.g. "example.com"
+
+	// Resolver defaults to net.DefaultResolver.
+	Resolver *net.Resolver
+}
+
+func (d *DNSSRVDiscoverer) Discover(ctx context.Context) ([]string, error) {
+	resolver := d.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	proto := d.Proto
+	if proto == "" {
+		proto = "tcp"
+	}
+
+	_, srvs, err := resolver.LookupSRV(ctx, d.Service, proto, d.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]string, 0, len(srvs))
+	for _, srv := range srvs {
+		nodes = append(nodes, net.JoinHostPort(strings.TrimSuffix(srv.Target, "."), strconv.Itoa(int(srv.Port))))
+	}
+	return nodes, nil
+}
+
+// kubernetes.EndpointsDiscoverer and staticfile.Discoverer live in the
+// memcache/kubernetes and memcache/staticfile subpackages, respectively, so
+// that using memcache.Client doesn't pull in client-go, fsnotify, or
+// yaml.v3. Both implement the Discoverer interface above.
@@ -0,0 +1,136 @@
+package memcache
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestDNSSRVDiscoverer_Discover(t *testing.T) {
+	t.Parallel()
+
+	addr, stop := startFakeSRVServer(t, []srvRecord{
+		{target: "node1.example.com.", port: 11211},
+		{target: "node2.example.com.", port: 11212},
+	})
+	defer stop()
+
+	d := &DNSSRVDiscoverer{
+		Service: "memcached",
+		Proto:   "tcp",
+		Domain:  "example.com",
+		Resolver: &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+				return net.Dial("udp", addr)
+			},
+		},
+	}
+
+	nodes, err := d.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	sort.Strings(nodes)
+
+	want := []string{"node1.example.com:11211", "node2.example.com:11212"}
+	if !reflect.DeepEqual(nodes, want) {
+		t.Fatalf("Discover() = %v, want %v", nodes, want)
+	}
+}
+
+type srvRecord struct {
+	target string
+	port   uint16
+}
+
+// startFakeSRVServer starts a UDP server that answers any SRV query with
+// records, so DNSSRVDiscoverer can be exercised without a real DNS server.
+func startFakeSRVServer(t *testing.T, records []srvRecord) (addr string, stop func()) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.ListenPacket() error = %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buf := make([]byte, 512)
+		for {
+			n, clientAddr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			resp := buildSRVResponse(buf[:n], records)
+			if _, err := conn.WriteTo(resp, clientAddr); err != nil {
+				return
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String(), func() {
+		_ = conn.Close()
+		<-done
+	}
+}
+
+// buildSRVResponse builds a minimal DNS response answering query (a single
+// question, as DNSSRVDiscoverer sends) with one SRV record per record. It
+// keeps only the header and question section of query, dropping any
+// EDNS0/additional section, so the answers that follow land in the right
+// place for a strict DNS parser.
+func buildSRVResponse(query []byte, records []srvRecord) []byte {
+	i := 12
+	for query[i] != 0 {
+		i += int(query[i]) + 1
+	}
+	qEnd := i + 1 + 4 // the zero label byte, then QTYPE and QCLASS
+
+	resp := make([]byte, qEnd)
+	copy(resp, query[:qEnd])
+
+	binary.BigEndian.PutUint16(resp[2:4], 0x8180)               // flags: response, no error
+	binary.BigEndian.PutUint16(resp[6:8], uint16(len(records))) // ANCOUNT
+	binary.BigEndian.PutUint16(resp[8:10], 0)                   // NSCOUNT
+	binary.BigEndian.PutUint16(resp[10:12], 0)                  // ARCOUNT
+
+	for _, r := range records {
+		resp = append(resp, 0xC0, 0x0C) // name: pointer to the question at offset 12
+		rdata := encodeSRVRData(r)
+		rr := make([]byte, 0, 10+len(rdata))
+		rr = binary.BigEndian.AppendUint16(rr, 33)                 // TYPE SRV
+		rr = binary.BigEndian.AppendUint16(rr, 1)                  // CLASS IN
+		rr = binary.BigEndian.AppendUint32(rr, 60)                 // TTL
+		rr = binary.BigEndian.AppendUint16(rr, uint16(len(rdata))) // RDLENGTH
+		rr = append(rr, rdata...)
+		resp = append(resp, rr...)
+	}
+	return resp
+}
+
+func encodeSRVRData(r srvRecord) []byte {
+	rdata := make([]byte, 0, 6+len(r.target)+1)
+	rdata = binary.BigEndian.AppendUint16(rdata, 0) // priority
+	rdata = binary.BigEndian.AppendUint16(rdata, 0) // weight
+	rdata = binary.BigEndian.AppendUint16(rdata, r.port)
+	rdata = append(rdata, encodeDNSName(r.target)...)
+	return rdata
+}
+
+func encodeDNSName(name string) []byte {
+	var out []byte
+	start := 0
+	for i := 0; i < len(name); i++ {
+		if name[i] == '.' {
+			out = append(out, byte(i-start))
+			out = append(out, name[start:i]...)
+			start = i + 1
+		}
+	}
+	return append(out, 0)
+}
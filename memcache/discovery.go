@@ -0,0 +1,204 @@
+package memcache
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// Discoverer discovers the current set of memcached node addresses for a
+// cluster from some external source, such as AWS ElastiCache auto
+// discovery, DNS, Kubernetes, or a static configuration file.
+type Discoverer interface {
+	Discover(ctx context.Context) ([]string, error)
+}
+
+// Discovery polls a Discoverer on an interval and reconciles whatever node
+// addresses it returns against a consistent-hash ServerList, so PickServer
+// always targets the current membership of the cluster.
+type Discovery struct {
+	discoverer   Discoverer
+	pollInterval time.Duration
+
+	mu         sync.RWMutex
+	serverList *ServerList
+
+	observer Observer
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Option configures a Discovery constructed by NewDiscovery or one of the
+// discoverer-specific constructors that wrap it, such as
+// NewAWSDiscoverySelector. An Option returns an error if it cannot apply
+// itself to d, e.g. WithTLS against a Discoverer that doesn't support TLS.
+type Option func(d *Discovery) error
+
+// NewDiscovery wraps a Discoverer in a Discovery. It runs an initial,
+// synchronous discover(), surfacing any error from it, and then starts a
+// background poller on an interval (default 1 hour, see
+// WithCustomPollInterval) that runs until ctx is done or Close is called.
+//
+// The initial discover() is given the same Close-scoped context as every
+// later poll, not the caller's ctx directly: some Discoverers (such as
+// kubernetes.EndpointsDiscoverer and staticfile.Discoverer) start a
+// background watch goroutine keyed off the context of their first Discover
+// call, and that goroutine must stop on Close rather than outlive it.
+func NewDiscovery(ctx context.Context, discoverer Discoverer, options ...Option) (*Discovery, error) {
+	d := &Discovery{
+		discoverer:   discoverer,
+		pollInterval: time.Hour,
+	}
+	for _, option := range options {
+		if err := option(d); err != nil {
+			return nil, err
+		}
+	}
+
+	pollCtx, cancel := context.WithCancel(ctx)
+	d.cancel = cancel
+
+	if err := d.discover(pollCtx); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	ticker := time.NewTicker(d.pollInterval)
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-pollCtx.Done():
+				return
+			case <-ticker.C:
+				_ = d.discover(pollCtx)
+			}
+		}
+	}()
+	return d, nil
+}
+
+// Close stops the background poller and waits for it to exit.
+func (d *Discovery) Close() error {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.wg.Wait()
+	return nil
+}
+
+// WithCustomPollInterval sets the poll interval for the Discovery. Default is 1 hour.
+func WithCustomPollInterval(interval time.Duration) Option {
+	return func(d *Discovery) error {
+		d.pollInterval = interval
+		return nil
+	}
+}
+
+// discover asks the underlying Discoverer for the current node list and
+// reconciles it against the ServerList's hash ring, adding and removing
+// only the servers that actually changed. If an Observer is configured, it
+// is notified of the attempt, the added/removed nodes and latency on
+// success, or the error on failure.
+func (d *Discovery) discover(ctx context.Context) error {
+	if d.observer != nil {
+		d.observer.OnDiscoverStart()
+	}
+	start := time.Now()
+
+	nodes, err := d.discoverer.Discover(ctx)
+	if err != nil {
+		d.reportDiscoverError(err)
+		return err
+	}
+	if len(nodes) == 0 {
+		d.reportDiscoverError(ErrAutoDiscover)
+		return ErrAutoDiscover
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var added, removed []string
+	if d.serverList == nil {
+		var serverList ServerList
+		if err := serverList.SetServers(nodes...); err != nil {
+			d.reportDiscoverError(err)
+			return err
+		}
+		d.serverList = &serverList
+		added = nodes
+	} else {
+		// nodes are whatever identity the Discoverer uses (often a hostname,
+		// e.g. from AWSAutoDiscoverer or DNSSRVDiscoverer), while
+		// currentNodes are already-resolved net.Addrs, so resolve nodes
+		// before comparing; comparing the raw strings directly would never
+		// match a hostname-identified node against its resolved form and
+		// treat every poll as a full add+remove of the whole membership.
+		currentNodes := append([]net.Addr(nil), d.serverList.addresses...)
+		resolved := make(map[string]string, len(nodes)) // resolved addr string -> raw node string
+		for _, node := range nodes {
+			addr, err := resolveServer(node)
+			if err != nil {
+				d.reportDiscoverError(err)
+				return err
+			}
+			resolved[addr.String()] = node
+		}
+
+		for resolvedAddr, node := range resolved {
+			if !containsNode(currentNodes, resolvedAddr) {
+				if err := d.serverList.AddServer(node); err != nil {
+					d.reportDiscoverError(err)
+					return err
+				}
+				added = append(added, node)
+			}
+		}
+		for _, addr := range currentNodes {
+			if _, ok := resolved[addr.String()]; !ok {
+				if err := d.serverList.RemoveServer(addr.String()); err != nil {
+					d.reportDiscoverError(err)
+					return err
+				}
+				removed = append(removed, addr.String())
+			}
+		}
+	}
+
+	if d.observer != nil {
+		d.observer.OnDiscoverSuccess(nodes, added, removed, time.Since(start))
+	}
+	return nil
+}
+
+func (d *Discovery) reportDiscoverError(err error) {
+	if d.observer != nil {
+		d.observer.OnDiscoverError(err)
+	}
+}
+
+func (d *Discovery) PickServer(key string) (net.Addr, error) {
+	addr, err := d.serverList.PickServer(key)
+	if err == nil && d.observer != nil {
+		d.observer.OnPickServer(key, addr)
+	}
+	return addr, err
+}
+
+func (d *Discovery) Each(f func(net.Addr) error) error {
+	return d.serverList.Each(f)
+}
+
+func containsNode(nodes []net.Addr, node string) bool {
+	for _, n := range nodes {
+		if n.String() == node {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package memcache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// watchingDiscoverer mimics kubernetes.EndpointsDiscoverer and
+// staticfile.Discoverer: on its first Discover call it starts a background
+// goroutine tied to the ctx it was given, exiting only when that ctx is
+// done.
+type watchingDiscoverer struct {
+	nodes []string
+
+	once    sync.Once
+	stopped chan struct{}
+}
+
+func newWatchingDiscoverer(nodes []string) *watchingDiscoverer {
+	return &watchingDiscoverer{nodes: nodes, stopped: make(chan struct{})}
+}
+
+func (w *watchingDiscoverer) Discover(ctx context.Context) ([]string, error) {
+	w.once.Do(func() {
+		go func() {
+			<-ctx.Done()
+			close(w.stopped)
+		}()
+	})
+	return w.nodes, nil
+}
+
+func TestDiscovery_Close_StopsDiscovererGoroutine(t *testing.T) {
+	t.Parallel()
+
+	wd := newWatchingDiscoverer([]string{"127.0.0.1:11211"})
+	d, err := NewDiscovery(context.Background(), wd)
+	if err != nil {
+		t.Fatalf("NewDiscovery() error = %v", err)
+	}
+
+	if err := d.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	select {
+	case <-wd.stopped:
+	case <-time.After(time.Second):
+		t.Fatal("discoverer's background goroutine was not stopped by Close()")
+	}
+}
+
+// shrinkingDiscoverer returns a fixed node list on its first Discover call
+// and a smaller subset on every call after that, so tests can exercise
+// discover()'s removal path.
+type shrinkingDiscoverer struct {
+	calls  int
+	full   []string
+	shrunk []string
+}
+
+func (s *shrinkingDiscoverer) Discover(ctx context.Context) ([]string, error) {
+	s.calls++
+	if s.calls == 1 {
+		return s.full, nil
+	}
+	return s.shrunk, nil
+}
+
+func TestDiscovery_discover_RemovesDroppedNodes(t *testing.T) {
+	t.Parallel()
+
+	full := []string{"127.0.0.1:11211", "127.0.0.1:11212", "127.0.0.1:11213"}
+	shrunk := []string{"127.0.0.1:11212", "127.0.0.1:11213"}
+	sd := &shrinkingDiscoverer{full: full, shrunk: shrunk}
+
+	d, err := NewDiscovery(context.Background(), sd)
+	if err != nil {
+		t.Fatalf("NewDiscovery() error = %v", err)
+	}
+	defer func() { _ = d.Close() }()
+
+	if err := d.discover(context.Background()); err != nil {
+		t.Fatalf("discover() error = %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		addr, err := d.PickServer("id_" + strconv.Itoa(i))
+		if err != nil {
+			t.Fatalf("PickServer() error = %v", err)
+		}
+		if addr.String() == "127.0.0.1:11211" {
+			t.Fatalf("PickServer() routed to %s, which discover() should have removed", addr)
+		}
+	}
+}
+
+// TestDiscovery_discover_HostnameNodesSurviveRepeatedPolls is a regression
+// test for comparing discovered node identity against the resolved net.Addr
+// form: with hostname-based nodes (what AWSAutoDiscoverer and
+// DNSSRVDiscoverer actually return), a naive string comparison between the
+// raw hostname and net.Addr.String()'s resolved form never matches, so an
+// unchanged node list got treated as a full remove-then-add on every poll
+// after the first.
+func TestDiscovery_discover_HostnameNodesSurviveRepeatedPolls(t *testing.T) {
+	t.Parallel()
+
+	nodes := []string{"localhost:11211", "localhost:11212"}
+	sd := &shrinkingDiscoverer{full: nodes, shrunk: nodes}
+
+	d, err := NewDiscovery(context.Background(), sd)
+	if err != nil {
+		t.Fatalf("NewDiscovery() error = %v", err)
+	}
+	defer func() { _ = d.Close() }()
+
+	if err := d.discover(context.Background()); err != nil {
+		t.Fatalf("second discover() error = %v", err)
+	}
+
+	if got := len(d.serverList.addresses); got != len(nodes) {
+		t.Fatalf("len(serverList.addresses) = %d, want %d (an unchanged poll should not remove any node)", got, len(nodes))
+	}
+	if _, err := d.PickServer("some key"); err != nil {
+		t.Fatalf("PickServer() error = %v, want nil", err)
+	}
+}
+
+// TestDiscovery_discover_RemovesDroppedHostnameNode covers the same removal
+// path as TestDiscovery_discover_RemovesDroppedNodes but with hostname
+// identities, since IP-literal addresses round-trip through
+// resolveServer/String() unchanged and can mask the comparison bug above.
+func TestDiscovery_discover_RemovesDroppedHostnameNode(t *testing.T) {
+	t.Parallel()
+
+	full := []string{"localhost:11211", "localhost:11212", "localhost:11213"}
+	shrunk := []string{"localhost:11212", "localhost:11213"}
+	sd := &shrinkingDiscoverer{full: full, shrunk: shrunk}
+
+	d, err := NewDiscovery(context.Background(), sd)
+	if err != nil {
+		t.Fatalf("NewDiscovery() error = %v", err)
+	}
+	defer func() { _ = d.Close() }()
+
+	if err := d.discover(context.Background()); err != nil {
+		t.Fatalf("second discover() error = %v", err)
+	}
+
+	if got := len(d.serverList.addresses); got != len(shrunk) {
+		t.Fatalf("len(serverList.addresses) = %d, want %d", got, len(shrunk))
+	}
+
+	removedAddr, err := resolveServer("localhost:11211")
+	if err != nil {
+		t.Fatalf("resolveServer() error = %v", err)
+	}
+	for i := 0; i < 1000; i++ {
+		addr, err := d.PickServer("id_" + strconv.Itoa(i))
+		if err != nil {
+			t.Fatalf("PickServer() error = %v", err)
+		}
+		if addr.String() == removedAddr.String() {
+			t.Fatalf("PickServer() routed to %s, which discover() should have removed", addr)
+		}
+	}
+}
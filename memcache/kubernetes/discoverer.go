@@ -0,0 +1,90 @@
+// Package kubernetes provides a memcache.Discoverer backed by a Kubernetes
+// Endpoints object. It is split out from the core memcache package so that
+// consumers who don't need it aren't forced to pull in client-go.
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ErrNoAddresses is returned when the Endpoints object has no ready
+// addresses yet.
+var ErrNoAddresses = errors.New("kubernetes: no addresses found in endpoints")
+
+// EndpointsDiscoverer discovers nodes from the ready addresses of a
+// Kubernetes Endpoints object, kept current via a client-go shared informer
+// so that Discover is a cache read rather than an API call. It implements
+// memcache.Discoverer.
+type EndpointsDiscoverer struct {
+	Clientset kubernetes.Interface
+	Namespace string
+	Name      string
+	Port      int
+
+	once sync.Once
+	mu   sync.RWMutex
+	addr []string
+}
+
+func (k *EndpointsDiscoverer) Discover(ctx context.Context) ([]string, error) {
+	k.once.Do(func() { k.startInformer(ctx) })
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if len(k.addr) == 0 {
+		return nil, ErrNoAddresses
+	}
+	nodes := make([]string, len(k.addr))
+	copy(nodes, k.addr)
+	return nodes, nil
+}
+
+func (k *EndpointsDiscoverer) startInformer(ctx context.Context) {
+	factory := informers.NewSharedInformerFactoryWithOptions(k.Clientset, 0, informers.WithNamespace(k.Namespace))
+	informer := factory.Core().V1().Endpoints().Informer()
+	_, _ = informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    k.handleEndpoints,
+		UpdateFunc: func(_, obj interface{}) { k.handleEndpoints(obj) },
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if endpoints, ok := obj.(*corev1.Endpoints); ok && endpoints.Name == k.Name {
+				k.setAddresses(nil)
+			}
+		},
+	})
+
+	go informer.Run(ctx.Done())
+	cache.WaitForCacheSync(ctx.Done(), informer.HasSynced)
+}
+
+func (k *EndpointsDiscoverer) handleEndpoints(obj interface{}) {
+	endpoints, ok := obj.(*corev1.Endpoints)
+	if !ok || endpoints.Name != k.Name {
+		return
+	}
+
+	var addrs []string
+	for _, subset := range endpoints.Subsets {
+		for _, addr := range subset.Addresses {
+			addrs = append(addrs, net.JoinHostPort(addr.IP, strconv.Itoa(k.Port)))
+		}
+	}
+	k.setAddresses(addrs)
+}
+
+func (k *EndpointsDiscoverer) setAddresses(addrs []string) {
+	k.mu.Lock()
+	k.addr = addrs
+	k.mu.Unlock()
+}
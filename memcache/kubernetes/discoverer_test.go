@@ -0,0 +1,54 @@
+package kubernetes
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEndpointsDiscoverer_Discover(t *testing.T) {
+	t.Parallel()
+
+	clientset := fake.NewSimpleClientset(&corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "memcached", Namespace: "default"},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}},
+		}},
+	})
+
+	d := &EndpointsDiscoverer{
+		Clientset: clientset,
+		Namespace: "default",
+		Name:      "memcached",
+		Port:      11211,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var nodes []string
+	var err error
+	for {
+		nodes, err = d.Discover(ctx)
+		if err == nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("Discover() error = %v", err)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	sort.Strings(nodes)
+	want := []string{"10.0.0.1:11211", "10.0.0.2:11211"}
+	if !reflect.DeepEqual(nodes, want) {
+		t.Fatalf("Discover() = %v, want %v", nodes, want)
+	}
+}
@@ -0,0 +1,163 @@
+package memcache
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Observer receives events from a Discovery as it polls for cluster
+// membership changes and as Discovery.PickServer routes keys to nodes.
+// Implementations must be safe for concurrent use and should return
+// quickly, since every method is called inline on the discovery/pick path.
+type Observer interface {
+	// OnDiscoverStart is called before each discovery attempt.
+	OnDiscoverStart()
+	// OnDiscoverSuccess is called after a discovery attempt that returned at
+	// least one node, with the full current node list, the nodes added and
+	// removed since the previous attempt, and how long the attempt took.
+	OnDiscoverSuccess(nodes []string, added, removed []string, latency time.Duration)
+	// OnDiscoverError is called when a discovery attempt fails, including
+	// ErrAutoDiscover when the Discoverer returns zero nodes.
+	OnDiscoverError(err error)
+	// OnPickServer is called after a successful PickServer call with the key
+	// and the node it was routed to.
+	OnPickServer(key string, addr net.Addr)
+}
+
+// WithObserver wires obs into the Discovery so discovery and PickServer
+// events are reported as they happen.
+func WithObserver(obs Observer) Option {
+	return func(d *Discovery) error {
+		d.observer = obs
+		return nil
+	}
+}
+
+// PrometheusObserver reports Discovery activity as Prometheus metrics:
+// counters for discover attempts and errors, a gauge for the node count
+// returned by the most recent successful discovery, a histogram of
+// discovery latency, and a counter of PickServer calls labeled by the node
+// each key was routed to.
+type PrometheusObserver struct {
+	DiscoverAttempts prometheus.Counter
+	DiscoverErrors   prometheus.Counter
+	NodeCount        prometheus.Gauge
+	DiscoverLatency  prometheus.Histogram
+	PickServerTotal  *prometheus.CounterVec
+}
+
+// NewPrometheusObserver builds a PrometheusObserver and registers its
+// metrics with reg.
+func NewPrometheusObserver(reg prometheus.Registerer) (*PrometheusObserver, error) {
+	p := &PrometheusObserver{
+		DiscoverAttempts: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "memcache_discover_attempts_total",
+			Help: "Total number of discovery attempts.",
+		}),
+		DiscoverErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "memcache_discover_errors_total",
+			Help: "Total number of discovery attempts that returned an error.",
+		}),
+		NodeCount: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "memcache_discover_nodes",
+			Help: "Number of nodes returned by the most recent successful discovery.",
+		}),
+		DiscoverLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "memcache_discover_duration_seconds",
+			Help:    "Latency of successful discovery attempts.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		PickServerTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "memcache_pick_server_total",
+			Help: "Total number of PickServer calls, labeled by the node the key was routed to.",
+		}, []string{"node"}),
+	}
+
+	for _, c := range []prometheus.Collector{p.DiscoverAttempts, p.DiscoverErrors, p.NodeCount, p.DiscoverLatency, p.PickServerTotal} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (p *PrometheusObserver) OnDiscoverStart() {
+	p.DiscoverAttempts.Inc()
+}
+
+func (p *PrometheusObserver) OnDiscoverSuccess(nodes []string, _, _ []string, latency time.Duration) {
+	p.NodeCount.Set(float64(len(nodes)))
+	p.DiscoverLatency.Observe(latency.Seconds())
+}
+
+func (p *PrometheusObserver) OnDiscoverError(error) {
+	p.DiscoverErrors.Inc()
+}
+
+func (p *PrometheusObserver) OnPickServer(_ string, addr net.Addr) {
+	p.PickServerTotal.WithLabelValues(addr.String()).Inc()
+}
+
+// OTelObserver starts an OpenTelemetry trace span around each discover()
+// call, annotating it with the node/added/removed counts on success or
+// recording the error on failure. PickServer is not traced, since it sits
+// on the hot path of every request.
+type OTelObserver struct {
+	tracer trace.Tracer
+
+	mu   sync.Mutex
+	span trace.Span
+}
+
+// NewOTelObserver builds an OTelObserver that starts spans with tracer.
+func NewOTelObserver(tracer trace.Tracer) *OTelObserver {
+	return &OTelObserver{tracer: tracer}
+}
+
+func (o *OTelObserver) OnDiscoverStart() {
+	_, span := o.tracer.Start(context.Background(), "memcache.discover")
+	o.mu.Lock()
+	o.span = span
+	o.mu.Unlock()
+}
+
+func (o *OTelObserver) OnDiscoverSuccess(nodes []string, added, removed []string, latency time.Duration) {
+	span := o.takeSpan()
+	if span == nil {
+		return
+	}
+	span.SetAttributes(
+		attribute.Int("memcache.node_count", len(nodes)),
+		attribute.Int("memcache.added_count", len(added)),
+		attribute.Int("memcache.removed_count", len(removed)),
+		attribute.Int64("memcache.latency_ms", latency.Milliseconds()),
+	)
+	span.End()
+}
+
+func (o *OTelObserver) OnDiscoverError(err error) {
+	span := o.takeSpan()
+	if span == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	span.End()
+}
+
+func (o *OTelObserver) OnPickServer(string, net.Addr) {}
+
+func (o *OTelObserver) takeSpan() trace.Span {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	span := o.span
+	o.span = nil
+	return span
+}
@@ -0,0 +1,95 @@
+package memcache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestPrometheusObserver(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+	p, err := NewPrometheusObserver(reg)
+	if err != nil {
+		t.Fatalf("NewPrometheusObserver() error = %v", err)
+	}
+
+	p.OnDiscoverStart()
+	p.OnDiscoverSuccess([]string{"a", "b"}, []string{"b"}, nil, 5*time.Millisecond)
+	p.OnDiscoverStart()
+	p.OnDiscoverError(errors.New("boom"))
+	p.OnPickServer("key1", &staticAddr{ntw: "tcp", str: "127.0.0.1:11211"})
+	p.OnPickServer("key2", &staticAddr{ntw: "tcp", str: "127.0.0.1:11211"})
+
+	if got := testutil.ToFloat64(p.DiscoverAttempts); got != 2 {
+		t.Fatalf("DiscoverAttempts = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(p.DiscoverErrors); got != 1 {
+		t.Fatalf("DiscoverErrors = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(p.NodeCount); got != 2 {
+		t.Fatalf("NodeCount = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(p.PickServerTotal.WithLabelValues("127.0.0.1:11211")); got != 2 {
+		t.Fatalf("PickServerTotal = %v, want 2", got)
+	}
+
+	var m dto.Metric
+	if err := p.DiscoverLatency.Write(&m); err != nil {
+		t.Fatalf("DiscoverLatency.Write() error = %v", err)
+	}
+	if got := m.GetHistogram().GetSampleCount(); got != 1 {
+		t.Fatalf("DiscoverLatency sample count = %v, want 1", got)
+	}
+}
+
+func TestOTelObserver(t *testing.T) {
+	t.Parallel()
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	o := NewOTelObserver(tp.Tracer("memcache-test"))
+
+	o.OnDiscoverStart()
+	o.OnDiscoverSuccess([]string{"a", "b"}, []string{"b"}, nil, 5*time.Millisecond)
+
+	spans := sr.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("len(spans) = %d, want 1", len(spans))
+	}
+	wantAttr(t, spans[0], "memcache.node_count", 2)
+	wantAttr(t, spans[0], "memcache.added_count", 1)
+	wantAttr(t, spans[0], "memcache.removed_count", 0)
+
+	o.OnDiscoverStart()
+	o.OnDiscoverError(errors.New("boom"))
+
+	spans = sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("len(spans) = %d, want 2", len(spans))
+	}
+	if got := spans[1].Status().Code; got != codes.Error {
+		t.Fatalf("span status = %v, want Error", got)
+	}
+}
+
+func wantAttr(t *testing.T, span sdktrace.ReadOnlySpan, key string, want int64) {
+	t.Helper()
+	for _, a := range span.Attributes() {
+		if string(a.Key) == key {
+			if got := a.Value.AsInt64(); got != want {
+				t.Fatalf("%s = %v, want %v", key, got, want)
+			}
+			return
+		}
+	}
+	t.Fatalf("attribute %s not found in %v", key, span.Attributes())
+}
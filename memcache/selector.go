@@ -0,0 +1,219 @@
+/*
+Copyright 2014 The gomemcache AUTHORS
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memcache
+
+import (
+	"errors"
+	"hash/crc32"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrNoServers is returned when no servers are configured or available.
+var ErrNoServers = errors.New("memcache: no servers configured or available")
+
+// defaultVNodes is the number of tokens placed on the hash ring for each
+// physical server when ServerList.VNodes is left at its zero value.
+const defaultVNodes = 160
+
+// ServerList is a consistent-hash ServerSelector. Its zero value is usable.
+// Servers are placed on a hash ring using VNodes virtual nodes each, so
+// adding or removing a single server only remaps the keys that land in
+// that server's arc of the ring, rather than reshuffling every key.
+type ServerList struct {
+	// VNodes overrides the number of ring tokens generated per physical
+	// server. Zero uses defaultVNodes.
+	VNodes int
+
+	mu        sync.RWMutex
+	addresses []net.Addr
+
+	ring      []uint32
+	ringNodes map[uint32]net.Addr
+}
+
+func (ss *ServerList) vnodes() int {
+	if ss.VNodes > 0 {
+		return ss.VNodes
+	}
+	return defaultVNodes
+}
+
+// resolveServer resolves a "host:port" or unix socket path into a net.Addr,
+// the same way SetServers always has.
+func resolveServer(server string) (net.Addr, error) {
+	if strings.Contains(server, "/") {
+		return net.ResolveUnixAddr("unix", server)
+	}
+	return net.ResolveTCPAddr("tcp", server)
+}
+
+// SetServers changes the set of servers to the given list, resolving each
+// one and rebuilding the hash ring from scratch. This is the right call for
+// the initial population of a ServerList; AddServer/RemoveServer are
+// cheaper for incremental membership changes.
+func (ss *ServerList) SetServers(servers ...string) error {
+	naddr := make([]net.Addr, len(servers))
+	for i, server := range servers {
+		addr, err := resolveServer(server)
+		if err != nil {
+			return err
+		}
+		naddr[i] = addr
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.addresses = naddr
+	ss.buildRingLocked()
+	return nil
+}
+
+// buildRingLocked rebuilds the ring from ss.addresses. ss.mu must be held
+// for writing.
+func (ss *ServerList) buildRingLocked() {
+	vnodes := ss.vnodes()
+	ring := make([]uint32, 0, len(ss.addresses)*vnodes)
+	ringNodes := make(map[uint32]net.Addr, len(ss.addresses)*vnodes)
+	for _, addr := range ss.addresses {
+		for i := 0; i < vnodes; i++ {
+			tok := ringToken(addr, i)
+			ring = append(ring, tok)
+			ringNodes[tok] = addr
+		}
+	}
+	sort.Slice(ring, func(i, j int) bool { return ring[i] < ring[j] })
+	ss.ring = ring
+	ss.ringNodes = ringNodes
+}
+
+func ringToken(addr net.Addr, replica int) uint32 {
+	return crc32.ChecksumIEEE([]byte(addr.String() + "#" + strconv.Itoa(replica)))
+}
+
+// AddServer incrementally adds a single server to the ring, touching only
+// that server's own tokens.
+func (ss *ServerList) AddServer(server string) error {
+	addr, err := resolveServer(server)
+	if err != nil {
+		return err
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	for _, existing := range ss.addresses {
+		if existing.String() == addr.String() {
+			return nil
+		}
+	}
+	ss.addresses = append(ss.addresses, addr)
+
+	if ss.ringNodes == nil {
+		ss.ringNodes = make(map[uint32]net.Addr)
+	}
+	for i := 0; i < ss.vnodes(); i++ {
+		tok := ringToken(addr, i)
+		ss.ringNodes[tok] = addr
+		idx := sort.Search(len(ss.ring), func(j int) bool { return ss.ring[j] >= tok })
+		ss.ring = append(ss.ring, 0)
+		copy(ss.ring[idx+1:], ss.ring[idx:])
+		ss.ring[idx] = tok
+	}
+	return nil
+}
+
+// RemoveServer incrementally removes a single server from the ring,
+// touching only that server's own tokens.
+func (ss *ServerList) RemoveServer(server string) error {
+	addr, err := resolveServer(server)
+	if err != nil {
+		return err
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	for i, existing := range ss.addresses {
+		if existing.String() == addr.String() {
+			ss.addresses = append(ss.addresses[:i], ss.addresses[i+1:]...)
+			break
+		}
+	}
+
+	for i := 0; i < ss.vnodes(); i++ {
+		tok := ringToken(addr, i)
+		delete(ss.ringNodes, tok)
+		idx := sort.Search(len(ss.ring), func(j int) bool { return ss.ring[j] >= tok })
+		if idx < len(ss.ring) && ss.ring[idx] == tok {
+			ss.ring = append(ss.ring[:idx], ss.ring[idx+1:]...)
+		}
+	}
+	return nil
+}
+
+// Each iterates over each server currently in the list, calling the given
+// function for each one. If the function returns a non-nil error, Each
+// stops and returns that error.
+func (ss *ServerList) Each(f func(net.Addr) error) error {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	for _, a := range ss.addresses {
+		if err := f(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PickServer returns the server owning key on the hash ring: the first
+// token at or after crc32(key), wrapping around to the first token if key
+// hashes past the end of the ring.
+func (ss *ServerList) PickServer(key string) (net.Addr, error) {
+	ss.mu.RLock()
+	if len(ss.addresses) == 0 {
+		ss.mu.RUnlock()
+		return nil, ErrNoServers
+	}
+	if len(ss.addresses) == 1 {
+		addr := ss.addresses[0]
+		ss.mu.RUnlock()
+		return addr, nil
+	}
+	if len(ss.ring) == 0 {
+		// addresses was populated directly rather than through
+		// SetServers/AddServer; upgrade to a write lock and build the
+		// ring lazily.
+		ss.mu.RUnlock()
+		ss.mu.Lock()
+		if len(ss.ring) == 0 {
+			ss.buildRingLocked()
+		}
+		ss.mu.Unlock()
+		ss.mu.RLock()
+	}
+
+	h := crc32.ChecksumIEEE([]byte(key))
+	idx := sort.Search(len(ss.ring), func(i int) bool { return ss.ring[i] >= h })
+	if idx == len(ss.ring) {
+		idx = 0
+	}
+	addr := ss.ringNodes[ss.ring[idx]]
+	ss.mu.RUnlock()
+	return addr, nil
+}
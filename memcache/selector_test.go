@@ -23,6 +23,17 @@ import (
 	"testing"
 )
 
+// staticAddr is a net.Addr fixture for tests that need to seed a
+// ServerList's ring with specific node identities without going through
+// SetServers' "host:port" parsing.
+type staticAddr struct {
+	ntw string
+	str string
+}
+
+func (a *staticAddr) Network() string { return a.ntw }
+func (a *staticAddr) String() string  { return a.str }
+
 func TestHashRing_GetTargetNode(t *testing.T) {
 	t.Parallel()
 	type fields struct {
@@ -40,11 +51,14 @@ func TestHashRing_GetTargetNode(t *testing.T) {
 		wantErr   bool
 	}{
 		{
+			// want reflects where this key lands on the consistent-hash ring,
+			// not node "1" - a ring gives no such guarantee, only that the
+			// same key always lands on the same node.
 			name:      "Test GetTargetNode",
 			fields:    fields{nodes: []string{"1", "2", "3", "4"}},
 			args:      args{key: "id_d5d25b3b-5acc-49fb-8cc7-0798ceeece69"},
 			iteration: 1_000_000,
-			want:      "1",
+			want:      "3",
 			wantErr:   false,
 		},
 		{
@@ -52,7 +66,7 @@ func TestHashRing_GetTargetNode(t *testing.T) {
 			fields:    fields{nodes: []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}},
 			args:      args{key: "id_ced5c816-f8a8-4f6e-bcc7-61472f099857"},
 			iteration: 1_000_000,
-			want:      "1",
+			want:      "10",
 			wantErr:   false,
 		},
 	}
@@ -99,7 +113,11 @@ func TestHashRing_Distribution(t *testing.T) {
 		t.Fatalf("PickServer() got = %v, want %v", len(distributionMap), len(replicas))
 	}
 
-	tolerance := 0.01
+	// With 160 virtual nodes per server, token placement on the ring isn't
+	// perfectly even the way mod-hashing was; 15% relative tolerance covers
+	// the imbalance a 4-node ring actually exhibits without masking a real
+	// regression.
+	tolerance := 0.15
 	expected := 0.25
 	for _, node := range replicas {
 		count := distributionMap[node]
@@ -135,6 +153,60 @@ func benchPickServer(b *testing.B, servers ...string) {
 	}
 }
 
+// BenchmarkKeyRemapOnNodeChange reports the percentage of keys that move to
+// a different server when a single node is added to a 10-node ring. With
+// virtual nodes this should land near 1/11 (~9%) rather than the ~100%
+// reshuffle a naive mod-N selector would produce.
+func BenchmarkKeyRemapOnNodeChange(b *testing.B) {
+	const numKeys = 100_000
+
+	var before ServerList
+	nodes := make([]string, 10)
+	for i := range nodes {
+		nodes[i] = "127.0.0.1:" + strconv.Itoa(11211+i)
+	}
+	if err := before.SetServers(nodes...); err != nil {
+		b.Fatal(err)
+	}
+
+	keys := make([]string, numKeys)
+	owners := make([]string, numKeys)
+	for i := range keys {
+		keys[i] = "id_" + strconv.Itoa(i)
+		addr, err := before.PickServer(keys[i])
+		if err != nil {
+			b.Fatal(err)
+		}
+		owners[i] = addr.String()
+	}
+
+	var after ServerList
+	if err := after.SetServers(nodes...); err != nil {
+		b.Fatal(err)
+	}
+	if err := after.AddServer("127.0.0.1:11222"); err != nil {
+		b.Fatal(err)
+	}
+
+	remapped := 0
+	for i, key := range keys {
+		addr, err := after.PickServer(key)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if addr.String() != owners[i] {
+			remapped++
+		}
+	}
+
+	pct := float64(remapped) / float64(numKeys) * 100
+	b.ReportMetric(pct, "percent-remapped")
+
+	for i := 0; i < b.N; i++ {
+		_, _ = after.PickServer(keys[i%numKeys])
+	}
+}
+
 func WithinTolerance(expected, got, tolerance float64) bool {
 	if expected == got {
 		return true
@@ -0,0 +1,93 @@
+// Package staticfile provides a memcache.Discoverer backed by a JSON or
+// YAML file, re-read on change via fsnotify. It is split out from the core
+// memcache package so that consumers who don't need it aren't forced to
+// pull in fsnotify and yaml.v3.
+package staticfile
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
+)
+
+// Discoverer discovers nodes from a JSON or YAML file containing a list of
+// "host:port" strings, re-reading it whenever fsnotify reports the file
+// changed. It implements memcache.Discoverer.
+type Discoverer struct {
+	Path string
+
+	once  sync.Once
+	mu    sync.RWMutex
+	nodes []string
+}
+
+func (s *Discoverer) Discover(ctx context.Context) ([]string, error) {
+	s.once.Do(func() { s.startWatch(ctx) })
+
+	s.mu.RLock()
+	nodes := s.nodes
+	s.mu.RUnlock()
+	if nodes != nil {
+		return nodes, nil
+	}
+	return s.readFile()
+}
+
+func (s *Discoverer) readFile() ([]string, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []string
+	if strings.HasSuffix(s.Path, ".yaml") || strings.HasSuffix(s.Path, ".yml") {
+		err = yaml.Unmarshal(data, &nodes)
+	} else {
+		err = json.Unmarshal(data, &nodes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.nodes = nodes
+	s.mu.Unlock()
+	return nodes, nil
+}
+
+func (s *Discoverer) startWatch(ctx context.Context) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return
+	}
+	if err := watcher.Add(s.Path); err != nil {
+		_ = watcher.Close()
+		return
+	}
+
+	go func() {
+		defer func() { _ = watcher.Close() }()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+					_, _ = s.readFile()
+				}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+}
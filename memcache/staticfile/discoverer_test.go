@@ -0,0 +1,46 @@
+package staticfile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiscoverer_Discover(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nodes.json")
+	if err := os.WriteFile(path, []byte(`["127.0.0.1:11211","127.0.0.1:11212"]`), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	d := &Discoverer{Path: path}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nodes, err := d.Discover(ctx)
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	want := []string{"127.0.0.1:11211", "127.0.0.1:11212"}
+	if !reflect.DeepEqual(nodes, want) {
+		t.Fatalf("Discover() = %v, want %v", nodes, want)
+	}
+
+	if err := os.WriteFile(path, []byte(`["127.0.0.1:11213"]`), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		nodes, err = d.Discover(ctx)
+		if err == nil && reflect.DeepEqual(nodes, []string{"127.0.0.1:11213"}) {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("Discover() never reflected the updated file, last = %v, err = %v", nodes, err)
+}